@@ -2,7 +2,6 @@ package main
 
 import (
 	"log"
-	"os"
 
 	"github.com/Mukam21/RAG_server-Golang/pkg/handlers"
 	"github.com/Mukam21/RAG_server-Golang/pkg/services"
@@ -17,11 +16,8 @@ func main() {
 		log.Println("Successfully loaded .env file")
 	}
 
-	geminiAPIKey := os.Getenv("GEMINI_API_KEY")
-	if geminiAPIKey == "" {
-		log.Fatal("GEMINI_API_KEY is not set. Please set it in .env file or environment variables.")
-	} else {
-		log.Println("GEMINI_API_KEY is set")
+	if err := services.InitProviders(); err != nil {
+		log.Fatal("Failed to initialize LLM providers:", err)
 	}
 
 	if err := services.InitDB(); err != nil {
@@ -33,6 +29,8 @@ func main() {
 	r.POST("/upload", handlers.UploadDocumentGin)
 	r.POST("/add", handlers.AddDocuments)
 	r.POST("/query", handlers.Query)
+	r.POST("/query/stream", handlers.QueryStream)
+	r.POST("/graph/explain", handlers.GraphExplain)
 
 	log.Println("Starting server on :8080")
 	if err := r.Run(":8080"); err != nil {
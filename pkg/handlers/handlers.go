@@ -42,14 +42,17 @@ func AddDocuments(c *gin.Context) {
 		wg.Add(1)
 		go func(text string) {
 			defer wg.Done()
-			embedding, err := services.GetEmbedding(text)
+
+			embedCtx, cancel := withTimeout(c.Request.Context(), "EMBED_TIMEOUT", defaultEmbedTimeout)
+			defer cancel()
+			embedding, err := services.GetEmbedding(embedCtx, text)
 			if err != nil {
 				mu.Lock()
 				errors = append(errors, err.Error())
 				mu.Unlock()
 				return
 			}
-			if err := services.AddDocument(text, embedding); err != nil {
+			if err := services.AddDocument(c.Request.Context(), text, embedding); err != nil {
 				mu.Lock()
 				errors = append(errors, err.Error())
 				mu.Unlock()
@@ -68,7 +71,10 @@ func AddDocuments(c *gin.Context) {
 }
 
 type QueryRequest struct {
-	Query string `json:"query" binding:"required"`
+	Query    string `json:"query" binding:"required"`
+	UseGraph bool   `json:"useGraph"`
+	MaxHops  int    `json:"maxHops"`
+	TopK     int    `json:"topK"`
 }
 
 func Query(c *gin.Context) {
@@ -84,27 +90,95 @@ func Query(c *gin.Context) {
 		return
 	}
 
-	embedding, err := services.GetEmbedding(trimmedQuery)
+	embedCtx, cancelEmbed := withTimeout(c.Request.Context(), "EMBED_TIMEOUT", defaultEmbedTimeout)
+	defer cancelEmbed()
+	embedding, err := services.GetEmbedding(embedCtx, trimmedQuery)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get query embedding: " + err.Error()})
 		return
 	}
 
-	context, err := services.SearchDocuments(embedding)
+	searchCtx, cancelSearch := withTimeout(c.Request.Context(), "SEARCH_TIMEOUT", defaultSearchTimeout)
+	defer cancelSearch()
+	results, err := services.SearchDocuments(searchCtx, embedding, trimmedQuery, req.UseGraph, req.MaxHops, req.TopK)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search documents: " + err.Error()})
 		return
 	}
 
-	response, err := services.GenerateResponse(trimmedQuery, context)
+	genCtx, cancelGen := withTimeout(c.Request.Context(), "GENERATE_TIMEOUT", defaultGenerateTimeout)
+	defer cancelGen()
+	contextText := renderContext(results)
+	response, err := services.GenerateResponse(genCtx, trimmedQuery, contextText)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate response: " + err.Error()})
 		return
 	}
 
+	if c.Query("format") == "structured" {
+		c.JSON(http.StatusOK, gin.H{"response": response, "results": results})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"response": response})
 }
 
+// renderContext flattens structured search results back into the plain-text
+// context block GenerateResponse expects.
+func renderContext(results []services.SearchResult) string {
+	parts := make([]string, 0, len(results))
+	for _, r := range results {
+		parts = append(parts, r.Content)
+	}
+	return strings.Join(parts, "\n")
+}
+
+type GraphExplainRequest struct {
+	Query   string `json:"query" binding:"required"`
+	DocID   int    `json:"docId" binding:"required"`
+	MaxHops int    `json:"maxHops"`
+	TopK    int    `json:"topK"`
+}
+
+// GraphExplain re-runs the multi-hop GraphRetriever for a query and reports
+// the actual triplet path that led to the requested document, so users can
+// debug why it was retrieved.
+func GraphExplain(c *gin.Context) {
+	var req GraphExplainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	trimmedQuery := strings.TrimSpace(req.Query)
+	if len(trimmedQuery) < 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query must be at least 3 characters long"})
+		return
+	}
+
+	embedCtx, cancelEmbed := withTimeout(c.Request.Context(), "EMBED_TIMEOUT", defaultEmbedTimeout)
+	defer cancelEmbed()
+	embedding, err := services.GetEmbedding(embedCtx, trimmedQuery)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get query embedding: " + err.Error()})
+		return
+	}
+
+	searchCtx, cancelSearch := withTimeout(c.Request.Context(), "SEARCH_TIMEOUT", defaultSearchTimeout)
+	defer cancelSearch()
+	path, found, err := services.ExplainGraphRetrieval(searchCtx, embedding, req.DocID, req.MaxHops, req.TopK)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to explain graph retrieval: " + err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document was not reached by the graph retriever for this query"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"path": path})
+}
+
 func UploadDocumentGin(c *gin.Context) {
 	file, _, err := c.Request.FormFile("document")
 	if err != nil {
@@ -119,13 +193,15 @@ func UploadDocumentGin(c *gin.Context) {
 		return
 	}
 
-	embedding, err := services.GetEmbedding(string(content))
+	embedCtx, cancelEmbed := withTimeout(c.Request.Context(), "EMBED_TIMEOUT", defaultEmbedTimeout)
+	defer cancelEmbed()
+	embedding, err := services.GetEmbedding(embedCtx, string(content))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate embedding: " + err.Error()})
 		return
 	}
 
-	if err := services.AddDocument(string(content), embedding); err != nil {
+	if err := services.AddDocument(c.Request.Context(), string(content), embedding); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save document: " + err.Error()})
 		return
 	}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Mukam21/RAG_server-Golang/pkg/services"
+	"github.com/gin-gonic/gin"
+)
+
+type streamEvent struct {
+	name string
+	data interface{}
+}
+
+// sendEvent delivers event on events, but gives up as soon as ctx is done so
+// the producer goroutine doesn't block forever once c.Stream's consumer has
+// stopped reading (e.g. the client disconnected). It reports whether the
+// event was actually sent.
+func sendEvent(ctx context.Context, events chan<- streamEvent, event streamEvent) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// QueryStream is the SSE counterpart to Query: it retrieves context the same
+// way, then streams the active generation provider's response token by token
+// instead of waiting for the full answer (a provider without streaming
+// support produces an `error` event). It emits `retrieval`, `token`, `done`, and `error`
+// events and stops promptly if the client disconnects.
+func QueryStream(c *gin.Context) {
+	var req QueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	trimmedQuery := strings.TrimSpace(req.Query)
+	if len(trimmedQuery) < 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query must be at least 3 characters long"})
+		return
+	}
+
+	embedCtx, cancelEmbed := withTimeout(c.Request.Context(), "EMBED_TIMEOUT", defaultEmbedTimeout)
+	defer cancelEmbed()
+	embedding, err := services.GetEmbedding(embedCtx, trimmedQuery)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get query embedding: " + err.Error()})
+		return
+	}
+
+	searchCtx, cancelSearch := withTimeout(c.Request.Context(), "SEARCH_TIMEOUT", defaultSearchTimeout)
+	defer cancelSearch()
+	results, err := services.SearchDocuments(searchCtx, embedding, trimmedQuery, req.UseGraph, req.MaxHops, req.TopK)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search documents: " + err.Error()})
+		return
+	}
+	contextText := renderContext(results)
+
+	events := make(chan streamEvent)
+	go func() {
+		defer close(events)
+
+		if !sendEvent(c.Request.Context(), events, streamEvent{name: "retrieval", data: results}) {
+			return
+		}
+
+		genCtx, cancelGen := withTimeout(c.Request.Context(), "GENERATE_TIMEOUT", defaultGenerateTimeout)
+		defer cancelGen()
+
+		usage, err := services.GenerateResponseStream(genCtx, trimmedQuery, contextText, func(token string) error {
+			if !sendEvent(c.Request.Context(), events, streamEvent{name: "token", data: gin.H{"text": token}}) {
+				return c.Request.Context().Err()
+			}
+			return nil
+		})
+		if err != nil {
+			sendEvent(c.Request.Context(), events, streamEvent{name: "error", data: gin.H{"error": err.Error()}})
+			return
+		}
+		sendEvent(c.Request.Context(), events, streamEvent{name: "done", data: usage})
+	}()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	c.Stream(func(w io.Writer) bool {
+		event, ok := <-events
+		if !ok {
+			return false
+		}
+		c.SSEvent(event.name, event.data)
+		return true
+	})
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+const (
+	defaultEmbedTimeout    = 10 * time.Second
+	defaultGenerateTimeout = 30 * time.Second
+	defaultSearchTimeout   = 10 * time.Second
+)
+
+// withTimeout derives a child context from parent bounded by the duration in
+// the named env var (e.g. EMBED_TIMEOUT=15s), falling back to fallback when
+// the env var is unset or unparsable.
+func withTimeout(parent context.Context, envVar string, fallback time.Duration) (context.Context, context.CancelFunc) {
+	d := fallback
+	if v := os.Getenv(envVar); v != "" {
+		if parsed, err := time.ParseDuration(v); err == nil {
+			d = parsed
+		}
+	}
+	return context.WithTimeout(parent, d)
+}
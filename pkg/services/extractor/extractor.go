@@ -0,0 +1,190 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Triplet is a single subject-predicate-object fact pulled out of a document.
+type Triplet struct {
+	Subject   string `json:"subject"`
+	Predicate string `json:"predicate"`
+	Object    string `json:"object"`
+}
+
+// Result is the output of a TripletExtractor run.
+type Result struct {
+	Tokens   []string  `json:"tokens"`
+	Triplets []Triplet `json:"triplets"`
+}
+
+// TripletExtractor turns raw document content into tokens and triplets.
+// Implementations may call out to an LLM, a local NLP model, or anything
+// else that can produce this shape.
+type TripletExtractor interface {
+	Extract(ctx context.Context, content string) (Result, error)
+}
+
+// generateEndpoint is a var, not a const, so tests can point it at an
+// httptest mock server the way pkg/services/llm/gemini.go does.
+var generateEndpoint = "https://generativelanguage.googleapis.com/v1beta/models/gemini-pro:generateContent?key="
+
+const extractionPrompt = `Extract tokens (distinct words/entities) and subject-predicate-object triplets from the text below.
+Respond with JSON only, matching this shape: {"tokens":["..."],"triplets":[{"subject":"...","predicate":"...","object":"..."}]}.
+
+Text:
+%s`
+
+// GeminiExtractor is the default TripletExtractor, backed by Gemini's
+// generateContent endpoint with responseMimeType set to application/json.
+// The expected shape is described only in the prompt: no responseSchema is
+// sent, so the API does not enforce it.
+type GeminiExtractor struct {
+	apiKey     string
+	httpClient *http.Client
+	maxRetries int
+	baseDelay  time.Duration
+}
+
+// defaultHTTPClient pools connections instead of dialing a fresh one per
+// extraction call.
+var defaultHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	},
+}
+
+// NewGeminiExtractor builds a GeminiExtractor using GEMINI_API_KEY from the
+// environment. It falls back to a rule-based tokenizer if the API key is
+// missing or every retry attempt fails.
+func NewGeminiExtractor(httpClient *http.Client) *GeminiExtractor {
+	if httpClient == nil {
+		httpClient = defaultHTTPClient
+	}
+	return &GeminiExtractor{
+		apiKey:     os.Getenv("GEMINI_API_KEY"),
+		httpClient: httpClient,
+		maxRetries: 3,
+		baseDelay:  500 * time.Millisecond,
+	}
+}
+
+func (g *GeminiExtractor) Extract(ctx context.Context, content string) (Result, error) {
+	if g.apiKey == "" {
+		return fallbackExtract(content), nil
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= g.maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := g.baseDelay * time.Duration(1<<uint(attempt-1))
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return fallbackExtract(content), nil
+			}
+		}
+
+		result, err := g.callGemini(ctx, content)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+	}
+
+	// The API is unavailable after every retry: degrade gracefully instead
+	// of failing the whole ingestion pipeline.
+	_ = lastErr
+	return fallbackExtract(content), nil
+}
+
+func (g *GeminiExtractor) callGemini(ctx context.Context, content string) (Result, error) {
+	payload := map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{
+				"parts": []map[string]string{
+					{"text": fmt.Sprintf(extractionPrompt, content)},
+				},
+			},
+		},
+		"generationConfig": map[string]interface{}{
+			"responseMimeType": "application/json",
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to marshal payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, generateEndpoint+g.apiKey, bytes.NewBuffer(body))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to send request to Gemini API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("Gemini API extraction request failed with status %s: %s", resp.Status, string(respBody))
+	}
+
+	var raw struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+	}
+	if err := json.Unmarshal(respBody, &raw); err != nil {
+		return Result{}, fmt.Errorf("failed to decode Gemini response: %v", err)
+	}
+	if len(raw.Candidates) == 0 || len(raw.Candidates[0].Content.Parts) == 0 {
+		return Result{}, fmt.Errorf("no valid response from Gemini API")
+	}
+
+	var result Result
+	if err := json.Unmarshal([]byte(raw.Candidates[0].Content.Parts[0].Text), &result); err != nil {
+		return Result{}, fmt.Errorf("failed to unmarshal extraction JSON: %v", err)
+	}
+	return result, nil
+}
+
+var wordRe = regexp.MustCompile(`[A-Za-zА-Яа-яЁё0-9]+`)
+
+// fallbackExtract is a best-effort, dependency-free tokenizer used when the
+// extraction API can't be reached. It produces tokens but no triplets.
+func fallbackExtract(content string) Result {
+	words := wordRe.FindAllString(content, -1)
+	seen := make(map[string]bool, len(words))
+	tokens := make([]string, 0, len(words))
+	for _, w := range words {
+		lw := strings.ToLower(w)
+		if seen[lw] {
+			continue
+		}
+		seen[lw] = true
+		tokens = append(tokens, lw)
+	}
+	return Result{Tokens: tokens}
+}
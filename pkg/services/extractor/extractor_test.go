@@ -0,0 +1,120 @@
+package extractor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func withAPIKey(t *testing.T, key string) {
+	t.Helper()
+	t.Setenv("GEMINI_API_KEY", key)
+}
+
+func TestGeminiExtractor_Extract_Success(t *testing.T) {
+	withAPIKey(t, "test-key")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"{\"tokens\":[\"cat\",\"mat\"],\"triplets\":[{\"subject\":\"cat\",\"predicate\":\"sat on\",\"object\":\"mat\"}]}"}]}}]}`))
+	}))
+	defer server.Close()
+	restoreEndpoint := generateEndpoint
+	generateEndpoint = server.URL + "?key="
+	defer func() { generateEndpoint = restoreEndpoint }()
+
+	e := NewGeminiExtractor(server.Client())
+	e.apiKey = "test-key"
+	e.baseDelay = time.Millisecond
+
+	result, err := e.Extract(context.Background(), "the cat sat on the mat")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Tokens) != 2 || result.Tokens[0] != "cat" {
+		t.Fatalf("unexpected tokens: %v", result.Tokens)
+	}
+	if len(result.Triplets) != 1 || result.Triplets[0].Predicate != "sat on" {
+		t.Fatalf("unexpected triplets: %v", result.Triplets)
+	}
+}
+
+func TestGeminiExtractor_Extract_FallsBackAfterRetriesExhausted(t *testing.T) {
+	withAPIKey(t, "test-key")
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	restoreEndpoint := generateEndpoint
+	generateEndpoint = server.URL + "?key="
+	defer func() { generateEndpoint = restoreEndpoint }()
+
+	e := NewGeminiExtractor(server.Client())
+	e.apiKey = "test-key"
+	e.maxRetries = 2
+	e.baseDelay = time.Millisecond
+
+	result, err := e.Extract(context.Background(), "hello world")
+	if err != nil {
+		t.Fatalf("expected graceful fallback, got error: %v", err)
+	}
+	if calls != e.maxRetries+1 {
+		t.Fatalf("expected %d attempts, got %d", e.maxRetries+1, calls)
+	}
+	if len(result.Tokens) != 2 {
+		t.Fatalf("expected fallback tokenizer to produce 2 tokens, got %v", result.Tokens)
+	}
+}
+
+func TestGeminiExtractor_Extract_NoAPIKeyUsesFallback(t *testing.T) {
+	withAPIKey(t, "")
+
+	e := NewGeminiExtractor(nil)
+	result, err := e.Extract(context.Background(), "no api key here")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Tokens) == 0 {
+		t.Fatalf("expected fallback tokens, got none")
+	}
+}
+
+func TestGeminiExtractor_CallGemini_ReportsStatusOnNonJSONErrorBody(t *testing.T) {
+	withAPIKey(t, "test-key")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("rate limited"))
+	}))
+	defer server.Close()
+	restoreEndpoint := generateEndpoint
+	generateEndpoint = server.URL + "?key="
+	defer func() { generateEndpoint = restoreEndpoint }()
+
+	e := NewGeminiExtractor(server.Client())
+	e.apiKey = "test-key"
+
+	_, err := e.callGemini(context.Background(), "hello")
+	if err == nil {
+		t.Fatalf("expected an error for a non-200 response")
+	}
+	if !strings.Contains(err.Error(), "429") {
+		t.Fatalf("expected the error to surface the HTTP status, got: %v", err)
+	}
+}
+
+func TestFallbackExtract_DedupesCaseInsensitive(t *testing.T) {
+	result := fallbackExtract("Go go GO gopher")
+	if len(result.Tokens) != 2 {
+		t.Fatalf("expected 2 distinct tokens, got %v", result.Tokens)
+	}
+	if len(result.Triplets) != 0 {
+		t.Fatalf("expected no triplets from fallback, got %v", result.Triplets)
+	}
+}
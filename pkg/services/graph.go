@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// graphDecay is the per-hop decay applied to a path's cumulative score, so
+// that tokens reached by a longer chain of PREDICATE edges count for less.
+const graphDecay = 0.5
+
+// defaultMaxHops bounds how many PREDICATE edges a GraphRetriever will
+// traverse when the caller doesn't specify maxHops.
+const defaultMaxHops = 2
+
+// TripletPath is the chain of tokens and predicates a GraphRetriever walked
+// to reach a token, along with its cumulative decayed score.
+type TripletPath struct {
+	Tokens     []string `json:"tokens"`
+	Predicates []string `json:"predicates"`
+	Score      float64  `json:"score"`
+}
+
+// GraphRetriever performs multi-hop expansion over the Neo4j triplet store:
+// it seeds with the top-K tokens from the vector index, then walks up to
+// maxHops PREDICATE edges, scoring each path by the seed token's
+// vector-index score plus Σ cosine(query, edge.embedding) * decay^hop.
+type GraphRetriever struct {
+	driver neo4j.DriverWithContext
+	decay  float64
+}
+
+// NewGraphRetriever builds a GraphRetriever backed by the given Neo4j driver.
+func NewGraphRetriever(driver neo4j.DriverWithContext) *GraphRetriever {
+	return &GraphRetriever{driver: driver, decay: graphDecay}
+}
+
+type tokenHop struct {
+	name       string
+	score      float64
+	tokens     []string
+	predicates []string
+}
+
+// GraphRetrievalResult is the ranked document list produced by a multi-hop
+// graph walk, plus the triplet path that led to each document so
+// /graph/explain can show callers why a document was retrieved.
+type GraphRetrievalResult struct {
+	Ranking    []rankedDoc
+	Content    map[int]string
+	PathsByDoc map[int]TripletPath
+}
+
+// Retrieve seeds a multi-hop walk from the vector index and aggregates
+// visited :Token nodes back to :Document nodes via CONTAINS.
+func (g *GraphRetriever) Retrieve(ctx context.Context, queryEmbedding []float32, maxHops, topK int) (GraphRetrievalResult, error) {
+	session := g.driver.NewSession(ctx, neo4j.SessionConfig{})
+	defer session.Close(ctx)
+
+	seedResult, err := session.Run(ctx, `
+		CALL db.index.vector.queryNodes('vector_index_token', $topK, $queryEmbedding)
+		YIELD node AS token, score
+		RETURN token.name AS name, score
+	`, map[string]interface{}{
+		"topK":           topK,
+		"queryEmbedding": queryEmbedding,
+	})
+	if err != nil {
+		return GraphRetrievalResult{}, fmt.Errorf("failed to seed graph retrieval: %v", err)
+	}
+
+	visited := make(map[string]float64)
+	pathsByToken := make(map[string]TripletPath)
+	var frontier []tokenHop
+
+	for seedResult.Next(ctx) {
+		record := seedResult.Record()
+		name, _ := record.Values[0].(string)
+		score, _ := record.Values[1].(float64)
+		visited[name] = score
+		pathsByToken[name] = TripletPath{Tokens: []string{name}, Score: score}
+		frontier = append(frontier, tokenHop{name: name, score: score, tokens: []string{name}})
+	}
+
+	for hop := 1; hop <= maxHops && len(frontier) > 0; hop++ {
+		var next []tokenHop
+		for _, tk := range frontier {
+			edgeResult, err := session.Run(ctx, `
+				MATCH (t:Token {name: $name})-[r:PREDICATE]->(o:Token)
+				RETURN r.name AS predicate, r.embedding AS embedding, o.name AS object
+			`, map[string]interface{}{"name": tk.name})
+			if err != nil {
+				return GraphRetrievalResult{}, fmt.Errorf("failed to expand token %q: %v", tk.name, err)
+			}
+
+			for edgeResult.Next(ctx) {
+				record := edgeResult.Record()
+				predicate, _ := record.Values[0].(string)
+				edgeEmbedding := toFloat32Slice(record.Values[1])
+				object, _ := record.Values[2].(string)
+
+				sim := cosineSimilarity(queryEmbedding, edgeEmbedding)
+				score := tk.score + sim*math.Pow(g.decay, float64(hop))
+
+				if existing, ok := visited[object]; ok && existing >= score {
+					continue
+				}
+				visited[object] = score
+
+				tokens := append(append([]string{}, tk.tokens...), object)
+				predicates := append(append([]string{}, tk.predicates...), predicate)
+				pathsByToken[object] = TripletPath{Tokens: tokens, Predicates: predicates, Score: score}
+				next = append(next, tokenHop{name: object, score: score, tokens: tokens, predicates: predicates})
+			}
+		}
+		frontier = next
+	}
+
+	return g.resolveDocuments(ctx, session, visited, pathsByToken)
+}
+
+func (g *GraphRetriever) resolveDocuments(ctx context.Context, session neo4j.SessionWithContext, visited map[string]float64, pathsByToken map[string]TripletPath) (GraphRetrievalResult, error) {
+	docScores := make(map[int]float64)
+	docContent := make(map[int]string)
+	pathsByDoc := make(map[int]TripletPath)
+
+	for token, score := range visited {
+		rows, err := session.Run(ctx, `
+			MATCH (t:Token {name: $name})-[:CONTAINS]->(d:Document)
+			RETURN d.id AS docID, d.content AS content
+		`, map[string]interface{}{"name": token})
+		if err != nil {
+			return GraphRetrievalResult{}, fmt.Errorf("failed to resolve documents for token %q: %v", token, err)
+		}
+
+		for rows.Next(ctx) {
+			record := rows.Record()
+			docID := toInt(record.Values[0])
+			content, _ := record.Values[1].(string)
+
+			docScores[docID] += score
+			docContent[docID] = content
+			if best, ok := pathsByDoc[docID]; !ok || score > best.Score {
+				pathsByDoc[docID] = pathsByToken[token]
+			}
+		}
+	}
+
+	ranking := make([]rankedDoc, 0, len(docScores))
+	for docID, content := range docContent {
+		ranking = append(ranking, rankedDoc{docID: docID, content: content})
+	}
+	sortRankedDocsByScore(ranking, docScores)
+	for i := range ranking {
+		ranking[i].rank = i + 1
+	}
+
+	return GraphRetrievalResult{Ranking: ranking, Content: docContent, PathsByDoc: pathsByDoc}, nil
+}
+
+func sortRankedDocsByScore(ranking []rankedDoc, scores map[int]float64) {
+	for i := 1; i < len(ranking); i++ {
+		for j := i; j > 0 && scores[ranking[j].docID] > scores[ranking[j-1].docID]; j-- {
+			ranking[j], ranking[j-1] = ranking[j-1], ranking[j]
+		}
+	}
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func toFloat32Slice(raw interface{}) []float32 {
+	values, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]float32, 0, len(values))
+	for _, v := range values {
+		switch n := v.(type) {
+		case float32:
+			out = append(out, n)
+		case float64:
+			out = append(out, float32(n))
+		}
+	}
+	return out
+}
+
+func toInt(raw interface{}) int {
+	switch n := raw.(type) {
+	case int64:
+		return int(n)
+	case int:
+		return n
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// fakeResult is a minimal neo4j.ResultWithContext backed by a fixed slice of
+// records. It embeds the interface so unexported methods (buffer, legacy,
+// errorHandler) are satisfied without reimplementing them.
+type fakeResult struct {
+	neo4j.ResultWithContext
+	records []*neo4j.Record
+	idx     int
+}
+
+func (f *fakeResult) Next(ctx context.Context) bool {
+	if f.idx >= len(f.records) {
+		return false
+	}
+	f.idx++
+	return true
+}
+
+func (f *fakeResult) Record() *neo4j.Record {
+	if f.idx == 0 || f.idx > len(f.records) {
+		return nil
+	}
+	return f.records[f.idx-1]
+}
+
+// fakeSession is a minimal neo4j.SessionWithContext whose Run is backed by a
+// test-supplied function. It embeds the interface so its unexported methods
+// (lastBookmark, executeQueryRead, ...) are satisfied without reimplementing
+// them.
+type fakeSession struct {
+	neo4j.SessionWithContext
+	run func(cypher string, params map[string]any) *fakeResult
+}
+
+func (f *fakeSession) Run(ctx context.Context, cypher string, params map[string]any, _ ...func(*neo4j.TransactionConfig)) (neo4j.ResultWithContext, error) {
+	return f.run(cypher, params), nil
+}
+
+func (f *fakeSession) Close(ctx context.Context) error { return nil }
+
+// fakeDriver is a minimal neo4j.DriverWithContext that always hands back the
+// same fakeSession. It embeds the interface so its unused methods (Target,
+// VerifyConnectivity, ...) are satisfied without reimplementing them.
+type fakeDriver struct {
+	neo4j.DriverWithContext
+	session *fakeSession
+}
+
+func (f *fakeDriver) NewSession(ctx context.Context, config neo4j.SessionConfig) neo4j.SessionWithContext {
+	return f.session
+}
+
+func newRecord(keys []string, values ...any) *neo4j.Record {
+	return &neo4j.Record{Keys: keys, Values: values}
+}
+
+// TestGraphRetriever_Retrieve_WalksHopsAndHandlesCycles exercises the
+// multi-hop walk end-to-end against a fake Neo4j session: a two-token cycle
+// (cat <-> mat) over 3 hops, checking that the walk terminates (bounded by
+// maxHops regardless of the cycle), that a re-visited token's score is only
+// updated when strictly higher, and that CONTAINS aggregates both tokens'
+// scores onto the one document they share.
+func TestGraphRetriever_Retrieve_WalksHopsAndHandlesCycles(t *testing.T) {
+	alignedEmbedding := []any{1.0, 0.0}
+
+	edges := map[string][]*neo4j.Record{
+		"cat": {newRecord([]string{"predicate", "embedding", "object"}, "sat_on", alignedEmbedding, "mat")},
+		"mat": {newRecord([]string{"predicate", "embedding", "object"}, "near", alignedEmbedding, "cat")},
+	}
+	contains := map[string][]*neo4j.Record{
+		"cat": {newRecord([]string{"docID", "content"}, int64(1), "a cat sat on a mat")},
+		"mat": {newRecord([]string{"docID", "content"}, int64(1), "a cat sat on a mat")},
+	}
+
+	session := &fakeSession{}
+	session.run = func(cypher string, params map[string]any) *fakeResult {
+		switch {
+		case strings.Contains(cypher, "vector.queryNodes"):
+			return &fakeResult{records: []*neo4j.Record{
+				newRecord([]string{"name", "score"}, "cat", 1.0),
+			}}
+		case strings.Contains(cypher, "PREDICATE]->(o:Token)"):
+			name, _ := params["name"].(string)
+			return &fakeResult{records: edges[name]}
+		case strings.Contains(cypher, "CONTAINS]->(d:Document)"):
+			name, _ := params["name"].(string)
+			return &fakeResult{records: contains[name]}
+		default:
+			t.Fatalf("unexpected cypher: %s", cypher)
+			return nil
+		}
+	}
+
+	retriever := NewGraphRetriever(&fakeDriver{session: session})
+
+	result, err := retriever.Retrieve(context.Background(), []float32{1, 0}, 3, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Ranking) != 1 || result.Ranking[0].docID != 1 {
+		t.Fatalf("expected a single document (1), got %+v", result.Ranking)
+	}
+	if result.Content[1] != "a cat sat on a mat" {
+		t.Fatalf("unexpected content for doc 1: %q", result.Content[1])
+	}
+
+	// cat: seed 1.0 -> hop2 (mat 1.5 -> cat) 1.5 + 1*0.5^2 = 1.75
+	// mat: hop1 (cat 1.0 -> mat) 1.0 + 1*0.5^1 = 1.5 -> hop3 (cat 1.75 -> mat) 1.75 + 1*0.5^3 = 1.875
+	// mat's final score (1.875) beats cat's (1.75), so its path wins the doc.
+	path, ok := result.PathsByDoc[1]
+	if !ok {
+		t.Fatalf("expected a triplet path for doc 1")
+	}
+	wantScore := 1.875
+	if math.Abs(path.Score-wantScore) > 1e-9 {
+		t.Fatalf("expected winning path score %v, got %v", wantScore, path.Score)
+	}
+	wantTokens := []string{"cat", "mat", "cat", "mat"}
+	if len(path.Tokens) != len(wantTokens) {
+		t.Fatalf("expected path tokens %v, got %v", wantTokens, path.Tokens)
+	}
+	for i, tok := range wantTokens {
+		if path.Tokens[i] != tok {
+			t.Fatalf("expected path tokens %v, got %v", wantTokens, path.Tokens)
+		}
+	}
+}
+
+func TestGraphRetriever_Retrieve_NoEdgesResolvesSeedDocumentOnly(t *testing.T) {
+	contains := map[string][]*neo4j.Record{
+		"cat": {newRecord([]string{"docID", "content"}, int64(7), "just a cat")},
+	}
+
+	session := &fakeSession{}
+	session.run = func(cypher string, params map[string]any) *fakeResult {
+		switch {
+		case strings.Contains(cypher, "vector.queryNodes"):
+			return &fakeResult{records: []*neo4j.Record{
+				newRecord([]string{"name", "score"}, "cat", 0.42),
+			}}
+		case strings.Contains(cypher, "PREDICATE]->(o:Token)"):
+			return &fakeResult{}
+		case strings.Contains(cypher, "CONTAINS]->(d:Document)"):
+			name, _ := params["name"].(string)
+			return &fakeResult{records: contains[name]}
+		default:
+			t.Fatalf("unexpected cypher: %s", cypher)
+			return nil
+		}
+	}
+
+	retriever := NewGraphRetriever(&fakeDriver{session: session})
+
+	result, err := retriever.Retrieve(context.Background(), []float32{1, 0}, 2, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Ranking) != 1 || result.Ranking[0].docID != 7 || result.Ranking[0].rank != 1 {
+		t.Fatalf("expected doc 7 ranked first, got %+v", result.Ranking)
+	}
+	if path := result.PathsByDoc[7]; len(path.Tokens) != 1 || path.Tokens[0] != "cat" {
+		t.Fatalf("expected the seed-only path [cat], got %+v", path)
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	a := []float32{1, 0, 0}
+	b := []float32{1, 0, 0}
+	if sim := cosineSimilarity(a, b); sim != 1 {
+		t.Fatalf("expected identical vectors to have similarity 1, got %v", sim)
+	}
+
+	orthogonal := []float32{0, 1, 0}
+	if sim := cosineSimilarity(a, orthogonal); sim != 0 {
+		t.Fatalf("expected orthogonal vectors to have similarity 0, got %v", sim)
+	}
+
+	if sim := cosineSimilarity(a, []float32{1, 0}); sim != 0 {
+		t.Fatalf("expected mismatched dimensions to return 0, got %v", sim)
+	}
+}
+
+func TestSortRankedDocsByScore(t *testing.T) {
+	ranking := []rankedDoc{{docID: 1}, {docID: 2}, {docID: 3}}
+	scores := map[int]float64{1: 0.1, 2: 0.9, 3: 0.5}
+
+	sortRankedDocsByScore(ranking, scores)
+
+	if ranking[0].docID != 2 || ranking[1].docID != 3 || ranking[2].docID != 1 {
+		t.Fatalf("expected docs sorted by descending score, got %v", ranking)
+	}
+}
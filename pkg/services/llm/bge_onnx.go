@@ -0,0 +1,120 @@
+//go:build onnx
+
+package llm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+func init() {
+	RegisterEmbedder("bge-small", func() (Embedder, error) { return NewBGESmallEmbedder() })
+}
+
+// bgeSmallDim is the embedding width of bge-small-en-v1.5.
+const bgeSmallDim = 384
+
+// BGESmallEmbedder runs the bge-small sentence embedding model locally via
+// onnxruntime-go, so embeddings can be produced without any network call.
+//
+// It uses a DynamicAdvancedSession rather than an AdvancedSession because
+// each call's input/attention-mask tensors are a different shape (one per
+// token count), and AdvancedSession requires fixed tensors bound at session
+// creation time.
+type BGESmallEmbedder struct {
+	session *ort.DynamicAdvancedSession
+	mu      sync.Mutex
+}
+
+// NewBGESmallEmbedder loads the ONNX model at BGE_MODEL_PATH.
+func NewBGESmallEmbedder() (*BGESmallEmbedder, error) {
+	modelPath := os.Getenv("BGE_MODEL_PATH")
+	if modelPath == "" {
+		return nil, fmt.Errorf("BGE_MODEL_PATH environment variable not set")
+	}
+
+	if err := ort.InitializeEnvironment(); err != nil {
+		return nil, fmt.Errorf("failed to initialize onnxruntime: %v", err)
+	}
+
+	session, err := ort.NewDynamicAdvancedSession(modelPath, []string{"input_ids", "attention_mask"}, []string{"last_hidden_state"}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bge-small model at %s: %v", modelPath, err)
+	}
+
+	return &BGESmallEmbedder{session: session}, nil
+}
+
+func (e *BGESmallEmbedder) Dimensions() int { return bgeSmallDim }
+
+func (e *BGESmallEmbedder) HealthCheck(ctx context.Context) error {
+	if e.session == nil {
+		return fmt.Errorf("bge-small model session is not initialized")
+	}
+	return nil
+}
+
+func (e *BGESmallEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	inputIDs, attentionMask := tokenizeForBGE(text)
+
+	inputTensor, err := ort.NewTensor(ort.NewShape(1, int64(len(inputIDs))), inputIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build input tensor: %v", err)
+	}
+	defer inputTensor.Destroy()
+
+	maskTensor, err := ort.NewTensor(ort.NewShape(1, int64(len(attentionMask))), attentionMask)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build attention mask tensor: %v", err)
+	}
+	defer maskTensor.Destroy()
+
+	outputTensor, err := ort.NewEmptyTensor[float32](ort.NewShape(1, int64(len(inputIDs)), bgeSmallDim))
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate output tensor: %v", err)
+	}
+	defer outputTensor.Destroy()
+
+	if err := e.session.Run([]ort.Value{inputTensor, maskTensor}, []ort.Value{outputTensor}); err != nil {
+		return nil, fmt.Errorf("failed to run bge-small inference: %v", err)
+	}
+
+	return meanPool(outputTensor.GetData(), len(inputIDs), bgeSmallDim), nil
+}
+
+// tokenizeForBGE and meanPool are deliberately minimal placeholders for the
+// real WordPiece tokenizer and pooling layer bge-small expects; swap in the
+// project's tokenizer when wiring up a real model file.
+func tokenizeForBGE(text string) (inputIDs, attentionMask []int64) {
+	words := []rune(text)
+	ids := make([]int64, 0, len(words))
+	mask := make([]int64, 0, len(words))
+	for i := range words {
+		ids = append(ids, int64(i)+1)
+		mask = append(mask, 1)
+	}
+	return ids, mask
+}
+
+func meanPool(hidden []float32, tokens, dim int) []float32 {
+	if tokens == 0 {
+		return make([]float32, dim)
+	}
+	pooled := make([]float32, dim)
+	for t := 0; t < tokens; t++ {
+		for d := 0; d < dim; d++ {
+			pooled[d] += hidden[t*dim+d]
+		}
+	}
+	for d := range pooled {
+		pooled[d] /= float32(tokens)
+	}
+	return pooled
+}
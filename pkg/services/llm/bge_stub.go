@@ -0,0 +1,11 @@
+//go:build !onnx
+
+package llm
+
+import "fmt"
+
+func init() {
+	RegisterEmbedder("bge-small", func() (Embedder, error) {
+		return nil, fmt.Errorf("bge-small support was not compiled in: rebuild with -tags onnx")
+	})
+}
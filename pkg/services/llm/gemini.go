@@ -0,0 +1,296 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterEmbedder("gemini", func() (Embedder, error) { return NewGeminiEmbedder(), nil })
+	RegisterGenerator("gemini", func() (Generator, error) { return NewGeminiGenerator(), nil })
+}
+
+var geminiHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	},
+}
+
+var (
+	geminiEmbeddingEndpoint = "https://generativelanguage.googleapis.com/v1beta/models/embedding-001:embedContent?key="
+	geminiGenerateEndpoint  = "https://generativelanguage.googleapis.com/v1beta/models/gemini-pro:generateContent?key="
+	geminiStreamEndpoint    = "https://generativelanguage.googleapis.com/v1beta/models/gemini-pro:streamGenerateContent?alt=sse&key="
+)
+
+// defaultEmbedDim is used when EMBED_DIM is unset.
+const defaultEmbedDim = 768
+
+func embedDim() int {
+	if v := os.Getenv("EMBED_DIM"); v != "" {
+		if dim, err := strconv.Atoi(v); err == nil && dim > 0 {
+			return dim
+		}
+	}
+	return defaultEmbedDim
+}
+
+// GeminiEmbedder is the default Embedder, calling Gemini's embedContent.
+type GeminiEmbedder struct {
+	apiKey string
+	dim    int
+}
+
+func NewGeminiEmbedder() *GeminiEmbedder {
+	return &GeminiEmbedder{apiKey: os.Getenv("GEMINI_API_KEY"), dim: embedDim()}
+}
+
+func (e *GeminiEmbedder) Dimensions() int { return e.dim }
+
+func (e *GeminiEmbedder) HealthCheck(ctx context.Context) error {
+	if e.apiKey == "" {
+		return fmt.Errorf("GEMINI_API_KEY environment variable not set")
+	}
+	return nil
+}
+
+func (e *GeminiEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if e.apiKey == "" {
+		return nil, fmt.Errorf("GEMINI_API_KEY environment variable not set")
+	}
+
+	payload := map[string]interface{}{
+		"model": "models/embedding-001",
+		"content": map[string]interface{}{
+			"parts": []map[string]string{
+				{"text": text},
+			},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, geminiEmbeddingEndpoint+e.apiKey, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := geminiHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to Gemini API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Gemini API embedding request failed with status %s: %s", resp.Status, string(respBody))
+	}
+
+	var result struct {
+		Embedding struct {
+			Values []float32 `json:"values"`
+		} `json:"embedding"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	if len(result.Embedding.Values) == 0 {
+		return nil, fmt.Errorf("empty embedding returned from Gemini API")
+	}
+
+	return result.Embedding.Values, nil
+}
+
+// GeminiGenerator is the default Generator, calling Gemini's
+// generateContent and streamGenerateContent endpoints.
+type GeminiGenerator struct {
+	apiKey string
+}
+
+func NewGeminiGenerator() *GeminiGenerator {
+	return &GeminiGenerator{apiKey: os.Getenv("GEMINI_API_KEY")}
+}
+
+func (g *GeminiGenerator) HealthCheck(ctx context.Context) error {
+	if g.apiKey == "" {
+		return fmt.Errorf("GEMINI_API_KEY environment variable not set")
+	}
+	return nil
+}
+
+func buildPrompt(query, context string) string {
+	return fmt.Sprintf("Контекст: %s\nВопрос: %s\nОтветь на вопрос, используя контекст.", context, query)
+}
+
+func (g *GeminiGenerator) Generate(ctx context.Context, query, context string) (string, error) {
+	if g.apiKey == "" {
+		return "", fmt.Errorf("GEMINI_API_KEY environment variable not set")
+	}
+
+	payload := map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{
+				"parts": []map[string]string{
+					{"text": buildPrompt(query, context)},
+				},
+			},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, geminiGenerateEndpoint+g.apiKey, bytes.NewBuffer(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := geminiHTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request to Gemini API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Gemini API generate request failed with status %s: %s", resp.Status, string(respBody))
+	}
+
+	var result struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	if len(result.Candidates) == 0 || len(result.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("no valid response from Gemini API")
+	}
+
+	return result.Candidates[0].Content.Parts[0].Text, nil
+}
+
+func (g *GeminiGenerator) GenerateStream(ctx context.Context, query, context string, onToken func(text string) error) (UsageStats, error) {
+	if g.apiKey == "" {
+		return UsageStats{}, fmt.Errorf("GEMINI_API_KEY environment variable not set")
+	}
+
+	payload := map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{
+				"parts": []map[string]string{
+					{"text": buildPrompt(query, context)},
+				},
+			},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return UsageStats{}, fmt.Errorf("failed to marshal payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, geminiStreamEndpoint+g.apiKey, bytes.NewBuffer(body))
+	if err != nil {
+		return UsageStats{}, fmt.Errorf("failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := geminiHTTPClient.Do(req)
+	if err != nil {
+		return UsageStats{}, fmt.Errorf("failed to send request to Gemini API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return UsageStats{}, fmt.Errorf("Gemini API stream request failed with status %s: %s", resp.Status, string(respBody))
+	}
+
+	var usage UsageStats
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		data := strings.TrimPrefix(line, "data: ")
+		if data == "" {
+			continue
+		}
+
+		var chunk struct {
+			Candidates []struct {
+				Content struct {
+					Parts []struct {
+						Text string `json:"text"`
+					} `json:"parts"`
+				} `json:"content"`
+			} `json:"candidates"`
+			UsageMetadata struct {
+				PromptTokenCount     int `json:"promptTokenCount"`
+				CandidatesTokenCount int `json:"candidatesTokenCount"`
+				TotalTokenCount      int `json:"totalTokenCount"`
+			} `json:"usageMetadata"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			continue
+		}
+
+		if chunk.UsageMetadata.TotalTokenCount > 0 {
+			usage = UsageStats{
+				PromptTokens:     chunk.UsageMetadata.PromptTokenCount,
+				CandidatesTokens: chunk.UsageMetadata.CandidatesTokenCount,
+				TotalTokens:      chunk.UsageMetadata.TotalTokenCount,
+			}
+		}
+
+		for _, c := range chunk.Candidates {
+			for _, p := range c.Content.Parts {
+				if p.Text == "" {
+					continue
+				}
+				if err := onToken(p.Text); err != nil {
+					return usage, err
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return usage, fmt.Errorf("failed to read Gemini stream: %v", err)
+	}
+
+	return usage, nil
+}
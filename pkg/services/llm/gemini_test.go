@@ -0,0 +1,121 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGeminiEmbedder_Embed_HonorsContextCancellation(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "test-key")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+			w.Write([]byte(`{"embedding":{"values":[0.1]}}`))
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	originalEndpoint := geminiEmbeddingEndpoint
+	geminiEmbeddingEndpoint = server.URL + "?key="
+	defer func() { geminiEmbeddingEndpoint = originalEndpoint }()
+
+	embedder := NewGeminiEmbedder()
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		wantErr bool
+	}{
+		{name: "cancelled before response", timeout: 20 * time.Millisecond, wantErr: true},
+		{name: "completes within deadline", timeout: time.Second, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			start := time.Now()
+			_, err := embedder.Embed(ctx, "some text")
+			elapsed := time.Since(start)
+
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected a cancellation error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr && elapsed > tt.timeout+100*time.Millisecond {
+				t.Fatalf("expected cancellation to be honored near the %v deadline, took %v", tt.timeout, elapsed)
+			}
+		})
+	}
+}
+
+func TestGeminiGenerator_GenerateStream_ParsesSSEChunks(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "test-key")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"Hel\"}]}}]}\n\n")
+		fmt.Fprint(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"lo\"}]}}]}\n\n")
+		fmt.Fprint(w, "data: {\"candidates\":[],\"usageMetadata\":{\"promptTokenCount\":5,\"candidatesTokenCount\":2,\"totalTokenCount\":7}}\n\n")
+	}))
+	defer server.Close()
+
+	originalEndpoint := geminiStreamEndpoint
+	geminiStreamEndpoint = server.URL + "?key="
+	defer func() { geminiStreamEndpoint = originalEndpoint }()
+
+	generator := NewGeminiGenerator()
+
+	var tokens []string
+	usage, err := generator.GenerateStream(context.Background(), "hi", "ctx", func(token string) error {
+		tokens = append(tokens, token)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tokens) != 2 || tokens[0] != "Hel" || tokens[1] != "lo" {
+		t.Fatalf("unexpected tokens: %v", tokens)
+	}
+	if usage.TotalTokens != 7 || usage.PromptTokens != 5 || usage.CandidatesTokens != 2 {
+		t.Fatalf("unexpected usage stats: %+v", usage)
+	}
+}
+
+func TestGeminiGenerator_GenerateStream_StopsWhenOnTokenErrors(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "test-key")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"first\"}]}}]}\n\n")
+		fmt.Fprint(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"second\"}]}}]}\n\n")
+	}))
+	defer server.Close()
+
+	originalEndpoint := geminiStreamEndpoint
+	geminiStreamEndpoint = server.URL + "?key="
+	defer func() { geminiStreamEndpoint = originalEndpoint }()
+
+	generator := NewGeminiGenerator()
+
+	var calls int
+	_, err := generator.GenerateStream(context.Background(), "hi", "ctx", func(token string) error {
+		calls++
+		return fmt.Errorf("client gone")
+	})
+	if err == nil {
+		t.Fatalf("expected onToken error to propagate")
+	}
+	if calls != 1 {
+		t.Fatalf("expected streaming to stop after the first token, got %d calls", calls)
+	}
+}
@@ -0,0 +1,77 @@
+// Package llm defines the provider-agnostic Embedder and Generator
+// interfaces used by services.GetEmbedding and services.GenerateResponse,
+// plus a small registry so a provider can be selected by name at startup.
+package llm
+
+import (
+	"context"
+	"fmt"
+)
+
+// Embedder turns text into a fixed-dimension vector.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+	Dimensions() int
+}
+
+// Generator produces a single answer from a query and retrieved context.
+type Generator interface {
+	Generate(ctx context.Context, query, context string) (string, error)
+}
+
+// StreamingGenerator is an optional capability a Generator may also
+// implement to support /query/stream. Providers that don't implement it
+// fall back to a clear "streaming not supported" error.
+type StreamingGenerator interface {
+	GenerateStream(ctx context.Context, query, context string, onToken func(text string) error) (UsageStats, error)
+}
+
+// HealthChecker lets a provider report whether it's usable (API key
+// present, model file found, endpoint reachable) so main.go can fail fast
+// at startup instead of on the first request.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// UsageStats mirrors provider-reported token usage for a generation call.
+type UsageStats struct {
+	PromptTokens     int `json:"promptTokens"`
+	CandidatesTokens int `json:"candidatesTokens"`
+	TotalTokens      int `json:"totalTokens"`
+}
+
+type embedderFactory func() (Embedder, error)
+type generatorFactory func() (Generator, error)
+
+var embedderFactories = map[string]embedderFactory{}
+var generatorFactories = map[string]generatorFactory{}
+
+// RegisterEmbedder makes an Embedder implementation available under name
+// for NewEmbedder. Implementations call this from an init() func.
+func RegisterEmbedder(name string, factory func() (Embedder, error)) {
+	embedderFactories[name] = factory
+}
+
+// RegisterGenerator makes a Generator implementation available under name
+// for NewGenerator. Implementations call this from an init() func.
+func RegisterGenerator(name string, factory func() (Generator, error)) {
+	generatorFactories[name] = factory
+}
+
+// NewEmbedder builds the Embedder registered under name.
+func NewEmbedder(name string) (Embedder, error) {
+	factory, ok := embedderFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown embed provider %q", name)
+	}
+	return factory()
+}
+
+// NewGenerator builds the Generator registered under name.
+func NewGenerator(name string) (Generator, error) {
+	factory, ok := generatorFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown generation provider %q", name)
+	}
+	return factory()
+}
@@ -0,0 +1,36 @@
+package llm
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewEmbedder_UnknownProvider(t *testing.T) {
+	if _, err := NewEmbedder("not-a-real-provider"); err == nil {
+		t.Fatal("expected an error for an unregistered embed provider")
+	}
+}
+
+func TestNewGenerator_UnknownProvider(t *testing.T) {
+	if _, err := NewGenerator("not-a-real-provider"); err == nil {
+		t.Fatal("expected an error for an unregistered generation provider")
+	}
+}
+
+func TestNewEmbedder_Gemini(t *testing.T) {
+	embedder, err := NewEmbedder("gemini")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if embedder.Dimensions() != defaultEmbedDim {
+		t.Fatalf("expected default dimensions %d, got %d", defaultEmbedDim, embedder.Dimensions())
+	}
+}
+
+func TestGeminiEmbedder_HealthCheckFailsWithoutAPIKey(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "")
+	embedder := NewGeminiEmbedder()
+	if err := embedder.HealthCheck(context.Background()); err == nil {
+		t.Fatal("expected health check to fail without GEMINI_API_KEY")
+	}
+}
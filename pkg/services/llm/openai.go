@@ -0,0 +1,198 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+func init() {
+	RegisterEmbedder("openai", func() (Embedder, error) { return NewOpenAICompatEmbedder(), nil })
+	RegisterGenerator("openai", func() (Generator, error) { return NewOpenAICompatGenerator(), nil })
+}
+
+var openAIHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	},
+}
+
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+func openAIBaseURL() string {
+	if v := os.Getenv("OPENAI_BASE_URL"); v != "" {
+		return v
+	}
+	return defaultOpenAIBaseURL
+}
+
+// OpenAICompatEmbedder talks to any server that implements OpenAI's
+// /embeddings API - OpenAI itself, Ollama, LM Studio, or vLLM.
+type OpenAICompatEmbedder struct {
+	baseURL string
+	model   string
+	apiKey  string
+	dim     int
+}
+
+func NewOpenAICompatEmbedder() *OpenAICompatEmbedder {
+	model := os.Getenv("OPENAI_EMBED_MODEL")
+	if model == "" {
+		model = "text-embedding-3-small"
+	}
+	return &OpenAICompatEmbedder{
+		baseURL: openAIBaseURL(),
+		model:   model,
+		apiKey:  os.Getenv("OPENAI_API_KEY"),
+		dim:     embedDim(),
+	}
+}
+
+func (e *OpenAICompatEmbedder) Dimensions() int { return e.dim }
+
+func (e *OpenAICompatEmbedder) HealthCheck(ctx context.Context) error {
+	if e.baseURL == "" {
+		return fmt.Errorf("OPENAI_BASE_URL environment variable not set")
+	}
+	if e.model == "" {
+		return fmt.Errorf("OPENAI_EMBED_MODEL environment variable not set")
+	}
+	return nil
+}
+
+func (e *OpenAICompatEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	payload := map[string]interface{}{
+		"model": e.model,
+		"input": text,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/embeddings", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := openAIHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %v", e.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embeddings request to %s failed with status %s: %s", e.baseURL, resp.Status, string(respBody))
+	}
+
+	var result struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+	if len(result.Data) == 0 || len(result.Data[0].Embedding) == 0 {
+		return nil, fmt.Errorf("empty embedding returned from %s", e.baseURL)
+	}
+
+	return result.Data[0].Embedding, nil
+}
+
+// OpenAICompatGenerator talks to any server that implements OpenAI's
+// /chat/completions API.
+type OpenAICompatGenerator struct {
+	baseURL string
+	model   string
+	apiKey  string
+}
+
+func NewOpenAICompatGenerator() *OpenAICompatGenerator {
+	model := os.Getenv("OPENAI_GEN_MODEL")
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	return &OpenAICompatGenerator{
+		baseURL: openAIBaseURL(),
+		model:   model,
+		apiKey:  os.Getenv("OPENAI_API_KEY"),
+	}
+}
+
+func (g *OpenAICompatGenerator) HealthCheck(ctx context.Context) error {
+	if g.baseURL == "" {
+		return fmt.Errorf("OPENAI_BASE_URL environment variable not set")
+	}
+	if g.model == "" {
+		return fmt.Errorf("OPENAI_GEN_MODEL environment variable not set")
+	}
+	return nil
+}
+
+func (g *OpenAICompatGenerator) Generate(ctx context.Context, query, context string) (string, error) {
+	payload := map[string]interface{}{
+		"model": g.model,
+		"messages": []map[string]string{
+			{"role": "user", "content": buildPrompt(query, context)},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+"/chat/completions", bytes.NewBuffer(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.apiKey)
+	}
+
+	resp, err := openAIHTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request to %s: %v", g.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("chat completion request to %s failed with status %s: %s", g.baseURL, resp.Status, string(respBody))
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("no valid response from %s", g.baseURL)
+	}
+
+	return result.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Mukam21/RAG_server-Golang/pkg/services/llm"
+)
+
+// UsageStats mirrors provider-reported token usage for a generation call.
+type UsageStats = llm.UsageStats
+
+var (
+	embedder      llm.Embedder
+	generator     llm.Generator
+	embedProvider string
+	genProvider   string
+)
+
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// InitProviders selects the embedding and generation providers named by
+// EMBED_PROVIDER and GEN_PROVIDER (both default to "gemini"), and runs each
+// provider's health check so main.go can fail fast with a clear error if
+// the selected provider is misconfigured.
+func InitProviders() error {
+	embedProvider = envOrDefault("EMBED_PROVIDER", "gemini")
+	genProvider = envOrDefault("GEN_PROVIDER", "gemini")
+
+	var err error
+	embedder, err = llm.NewEmbedder(embedProvider)
+	if err != nil {
+		return fmt.Errorf("failed to initialize embed provider %q: %v", embedProvider, err)
+	}
+	if err := healthCheck(embedder); err != nil {
+		return fmt.Errorf("embed provider %q failed health check: %v", embedProvider, err)
+	}
+
+	generator, err = llm.NewGenerator(genProvider)
+	if err != nil {
+		return fmt.Errorf("failed to initialize generation provider %q: %v", genProvider, err)
+	}
+	if err := healthCheck(generator); err != nil {
+		return fmt.Errorf("generation provider %q failed health check: %v", genProvider, err)
+	}
+
+	return nil
+}
+
+func healthCheck(provider interface{}) error {
+	hc, ok := provider.(llm.HealthChecker)
+	if !ok {
+		return nil
+	}
+	return hc.HealthCheck(context.Background())
+}
+
+// EmbedDimensions reports the active embed provider's vector width, so
+// InitDB can size the documents.embedding column and Neo4j vector index to
+// match.
+func EmbedDimensions() int {
+	return embedder.Dimensions()
+}
+
+func GetEmbedding(ctx context.Context, text string) ([]float32, error) {
+	return embedder.Embed(ctx, text)
+}
+
+func GenerateResponse(ctx context.Context, query, context string) (string, error) {
+	return generator.Generate(ctx, query, context)
+}
+
+// GenerateResponseStream streams a response token by token if the active
+// generation provider supports it.
+func GenerateResponseStream(ctx context.Context, query, context string, onToken func(text string) error) (UsageStats, error) {
+	streaming, ok := generator.(llm.StreamingGenerator)
+	if !ok {
+		return UsageStats{}, fmt.Errorf("generation provider %q does not support streaming", genProvider)
+	}
+	return streaming.GenerateStream(ctx, query, context, onToken)
+}
@@ -0,0 +1,22 @@
+package services
+
+import "testing"
+
+func TestInitProviders_UnknownProviderFailsFast(t *testing.T) {
+	t.Setenv("EMBED_PROVIDER", "not-a-real-provider")
+	t.Setenv("GEN_PROVIDER", "gemini")
+
+	if err := InitProviders(); err == nil {
+		t.Fatal("expected InitProviders to fail fast for an unknown EMBED_PROVIDER")
+	}
+}
+
+func TestInitProviders_DefaultsToGeminiAndFailsHealthCheckWithoutAPIKey(t *testing.T) {
+	t.Setenv("EMBED_PROVIDER", "")
+	t.Setenv("GEN_PROVIDER", "")
+	t.Setenv("GEMINI_API_KEY", "")
+
+	if err := InitProviders(); err == nil {
+		t.Fatal("expected InitProviders to fail its health check without GEMINI_API_KEY")
+	}
+}
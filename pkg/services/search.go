@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// MatchLevel describes how much of a result's content matched the query.
+type MatchLevel string
+
+const (
+	MatchLevelNone    MatchLevel = "none"
+	MatchLevelPartial MatchLevel = "partial"
+	MatchLevelFull    MatchLevel = "full"
+)
+
+// Source identifies which retrieval path produced a SearchResult.
+type Source string
+
+const (
+	SourceVector Source = "vector"
+	SourceGraph  Source = "graph"
+	SourceHybrid Source = "hybrid"
+)
+
+// Match is a single highlighted token within a SearchResult's content.
+type Match struct {
+	Value            string     `json:"value"`
+	MatchLevel       MatchLevel `json:"matchLevel"`
+	MatchedWords     []string   `json:"matchedWords,omitempty"`
+	FullyHighlighted *bool      `json:"fullyHighlighted,omitempty"`
+}
+
+// SearchResult is the structured shape returned by the hybrid retriever, in
+// place of the single opaque context string SearchDocuments used to return.
+type SearchResult struct {
+	DocID   int     `json:"docId"`
+	Content string  `json:"content"`
+	Score   float64 `json:"score"`
+	Source  Source  `json:"source"`
+	Matches []Match `json:"matches"`
+}
+
+// rrfK is the standard Reciprocal Rank Fusion damping constant.
+const rrfK = 60
+
+type rankedDoc struct {
+	docID   int
+	content string
+	rank    int
+}
+
+// reciprocalRankFusion combines several ranked lists into a single score per
+// document id using score = Σ 1/(k+rank_i).
+func reciprocalRankFusion(rankings ...[]rankedDoc) map[int]float64 {
+	scores := make(map[int]float64)
+	for _, ranking := range rankings {
+		for _, d := range ranking {
+			scores[d.docID] += 1.0 / float64(rrfK+d.rank)
+		}
+	}
+	return scores
+}
+
+func vectorRanking(ctx context.Context, queryEmbedding []float32, limit int) ([]rankedDoc, map[int]string, error) {
+	rows, err := pgConn.Query(ctx, `
+		SELECT id, content
+		FROM documents
+		ORDER BY embedding <-> $1
+		LIMIT $2
+	`, queryEmbedding, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var ranking []rankedDoc
+	contentByID := make(map[int]string)
+	rank := 1
+	for rows.Next() {
+		var docID int
+		var content string
+		if err := rows.Scan(&docID, &content); err != nil {
+			return nil, nil, err
+		}
+		ranking = append(ranking, rankedDoc{docID: docID, content: content, rank: rank})
+		contentByID[docID] = content
+		rank++
+	}
+	return ranking, contentByID, rows.Err()
+}
+
+func fullTextRanking(ctx context.Context, query string, limit int) ([]rankedDoc, map[int]string, error) {
+	rows, err := pgConn.Query(ctx, `
+		SELECT id, content
+		FROM documents
+		WHERE to_tsvector('english', content) @@ plainto_tsquery('english', $1)
+		ORDER BY ts_rank(to_tsvector('english', content), plainto_tsquery('english', $1)) DESC
+		LIMIT $2
+	`, query, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var ranking []rankedDoc
+	contentByID := make(map[int]string)
+	rank := 1
+	for rows.Next() {
+		var docID int
+		var content string
+		if err := rows.Scan(&docID, &content); err != nil {
+			return nil, nil, err
+		}
+		ranking = append(ranking, rankedDoc{docID: docID, content: content, rank: rank})
+		contentByID[docID] = content
+		rank++
+	}
+	return ranking, contentByID, rows.Err()
+}
+
+// minStemOverlap is the shortest shared prefix, in runes, that counts two
+// words as a stemmed/partial match (e.g. "graph" and "graphs").
+const minStemOverlap = 3
+
+// highlightMatches finds query words that appear in content: an exact word
+// match is reported as MatchLevelFull, and a word that shares a stem with a
+// query word (e.g. a plural or suffixed form) is reported as
+// MatchLevelPartial. Words with no overlap at all are simply omitted, so the
+// slice only ever contains MatchLevelFull/MatchLevelPartial entries —
+// MatchLevelNone describes the absence of a Match, not a Match value.
+func highlightMatches(query, content string) []Match {
+	querySet := make(map[string]bool)
+	var queryWords []string
+	for _, w := range strings.Fields(strings.ToLower(query)) {
+		qw := strings.Trim(w, ".,!?;:\"'()")
+		if qw == "" {
+			continue
+		}
+		if !querySet[qw] {
+			queryWords = append(queryWords, qw)
+		}
+		querySet[qw] = true
+	}
+
+	var matches []Match
+	for _, w := range strings.Fields(content) {
+		lw := strings.ToLower(strings.Trim(w, ".,!?;:\"'()"))
+		if lw == "" {
+			continue
+		}
+
+		if querySet[lw] {
+			full := true
+			matches = append(matches, Match{
+				Value:            w,
+				MatchLevel:       MatchLevelFull,
+				MatchedWords:     []string{lw},
+				FullyHighlighted: &full,
+			})
+			continue
+		}
+
+		if qw, ok := stemMatch(lw, queryWords); ok {
+			partial := false
+			matches = append(matches, Match{
+				Value:            w,
+				MatchLevel:       MatchLevelPartial,
+				MatchedWords:     []string{qw},
+				FullyHighlighted: &partial,
+			})
+		}
+	}
+	return matches
+}
+
+// stemMatch reports whether lw is a stemmed form of one of queryWords: one
+// word is a prefix of the other, and they share at least minStemOverlap
+// runes.
+func stemMatch(lw string, queryWords []string) (string, bool) {
+	for _, qw := range queryWords {
+		if qw == lw {
+			continue
+		}
+		shared := commonPrefixLen(lw, qw)
+		if utf8.RuneCountInString(lw[:shared]) < minStemOverlap {
+			continue
+		}
+		if shared == len(qw) || shared == len(lw) {
+			return qw, true
+		}
+	}
+	return "", false
+}
+
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// hybridSearch runs pgvector kNN and a Postgres full-text tsvector query in
+// parallel, fuses the two ranked lists with Reciprocal Rank Fusion, and
+// attaches per-token highlights for each result.
+func hybridSearch(ctx context.Context, queryEmbedding []float32, query string, topK int) ([]SearchResult, error) {
+	type rankResult struct {
+		ranking []rankedDoc
+		content map[int]string
+		err     error
+	}
+
+	vecCh := make(chan rankResult, 1)
+	ftCh := make(chan rankResult, 1)
+
+	go func() {
+		ranking, content, err := vectorRanking(ctx, queryEmbedding, topK)
+		vecCh <- rankResult{ranking, content, err}
+	}()
+	go func() {
+		ranking, content, err := fullTextRanking(ctx, query, topK)
+		ftCh <- rankResult{ranking, content, err}
+	}()
+
+	vec := <-vecCh
+	if vec.err != nil {
+		return nil, vec.err
+	}
+	ft := <-ftCh
+	if ft.err != nil {
+		return nil, ft.err
+	}
+
+	scores := reciprocalRankFusion(vec.ranking, ft.ranking)
+
+	contentByID := make(map[int]string, len(vec.content)+len(ft.content))
+	inVector := make(map[int]bool, len(vec.content))
+	inFullText := make(map[int]bool, len(ft.content))
+	for id, c := range vec.content {
+		contentByID[id] = c
+		inVector[id] = true
+	}
+	for id, c := range ft.content {
+		contentByID[id] = c
+		inFullText[id] = true
+	}
+
+	results := make([]SearchResult, 0, len(contentByID))
+	for docID, content := range contentByID {
+		source := SourceVector
+		if inFullText[docID] {
+			source = SourceHybrid
+		}
+		results = append(results, SearchResult{
+			DocID:   docID,
+			Content: content,
+			Score:   scores[docID],
+			Source:  source,
+			Matches: highlightMatches(query, content),
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > topK {
+		results = results[:topK]
+	}
+	return results, nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import "testing"
+
+func TestReciprocalRankFusion(t *testing.T) {
+	vector := []rankedDoc{{docID: 1, rank: 1}, {docID: 2, rank: 2}}
+	fullText := []rankedDoc{{docID: 2, rank: 1}, {docID: 3, rank: 2}}
+
+	scores := reciprocalRankFusion(vector, fullText)
+
+	if scores[2] <= scores[1] || scores[2] <= scores[3] {
+		t.Fatalf("expected doc 2 (present in both rankings) to score highest, got %v", scores)
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	matches := highlightMatches("golang graph rag", "This is a golang RAG server with a graph database.")
+
+	if len(matches) != 3 {
+		t.Fatalf("expected 3 matches, got %d: %v", len(matches), matches)
+	}
+	for _, m := range matches {
+		if m.MatchLevel != MatchLevelFull {
+			t.Fatalf("expected full match level, got %v", m.MatchLevel)
+		}
+	}
+}
+
+func TestHighlightMatches_ReportsPartialForStemmedWords(t *testing.T) {
+	matches := highlightMatches("graph database", "This server indexes graphs in a databases-backed index.")
+
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d: %v", len(matches), matches)
+	}
+	for _, m := range matches {
+		if m.MatchLevel != MatchLevelPartial {
+			t.Fatalf("expected partial match level for a stemmed word, got %v", m.MatchLevel)
+		}
+		if m.FullyHighlighted == nil || *m.FullyHighlighted {
+			t.Fatalf("expected FullyHighlighted=false for a partial match")
+		}
+	}
+}
+
+func TestHighlightMatches_OmitsNonMatchingWords(t *testing.T) {
+	matches := highlightMatches("golang", "This sentence shares nothing with the query.")
+
+	if len(matches) != 0 {
+		t.Fatalf("expected no matches, got %d: %v", len(matches), matches)
+	}
+}
@@ -1,54 +1,61 @@
 package services
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
-	"strings"
+	"sort"
 
+	"github.com/Mukam21/RAG_server-Golang/pkg/services/extractor"
 	"github.com/jackc/pgx/v5"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 )
 
 var pgConn *pgx.Conn
 var neo4jDriver neo4j.DriverWithContext
+var defaultExtractor extractor.TripletExtractor = extractor.NewGeminiExtractor(nil)
+var graphRetriever *GraphRetriever
 
-func init() {
+// InitDB connects to Postgres and, if configured, Neo4j, and creates the
+// documents table and vector indexes. The embedding column and Neo4j vector
+// index are sized to match the active embed provider (see EmbedDimensions)
+// when they are first created; both use IF NOT EXISTS, so an existing table
+// or index keeps the dimension it was created with.
+func InitDB() error {
 	var err error
 	pgPassword := os.Getenv("PG_PASSWORD")
 	if pgPassword == "" {
-		panic("PG_PASSWORD environment variable not set")
+		return fmt.Errorf("PG_PASSWORD environment variable not set")
 	}
 	connStr := fmt.Sprintf("postgres://postgres:%s@localhost:5438/postgres?sslmode=disable", pgPassword)
 	pgConn, err = pgx.Connect(context.Background(), connStr)
 	if err != nil {
-		panic(fmt.Sprintf("failed to connect to PostgreSQL: %v", err))
+		return fmt.Errorf("failed to connect to PostgreSQL: %v", err)
 	}
 
 	_, err = pgConn.Exec(context.Background(), "CREATE EXTENSION IF NOT EXISTS vector")
 	if err != nil {
-		panic(fmt.Sprintf("failed to create pgvector extension: %v", err))
+		return fmt.Errorf("failed to create pgvector extension: %v", err)
 	}
 
-	_, err = pgConn.Exec(context.Background(), `
+	dim := EmbedDimensions()
+
+	_, err = pgConn.Exec(context.Background(), fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS documents (
 			id SERIAL PRIMARY KEY,
 			content TEXT NOT NULL,
-			embedding VECTOR(768)
+			embedding VECTOR(%d)
 		)
-	`)
+	`, dim))
 	if err != nil {
-		panic(fmt.Sprintf("failed to create documents table: %v", err))
+		return fmt.Errorf("failed to create documents table: %v", err)
 	}
 
 	_, err = pgConn.Exec(context.Background(), `
 		CREATE INDEX IF NOT EXISTS documents_embedding_idx ON documents USING hnsw (embedding vector_l2_ops)
 	`)
 	if err != nil {
-		panic(fmt.Sprintf("failed to create index: %v", err))
+		return fmt.Errorf("failed to create index: %v", err)
 	}
 
 	neo4jURI := os.Getenv("NEO4J_URI")
@@ -57,26 +64,30 @@ func init() {
 		neo4jPassword := os.Getenv("NEO4J_PASSWORD")
 		neo4jDriver, err = neo4j.NewDriverWithContext(neo4jURI, neo4j.BasicAuth(neo4jUser, neo4jPassword, ""))
 		if err != nil {
-			panic(fmt.Sprintf("Failed to connect to Neo4j: %v", err))
+			return fmt.Errorf("failed to connect to Neo4j: %v", err)
 		}
 
 		ctx := context.Background()
 		session := neo4jDriver.NewSession(ctx, neo4j.SessionConfig{})
 		defer session.Close(ctx)
-		_, err = session.Run(ctx, `
+		_, err = session.Run(ctx, fmt.Sprintf(`
 			CREATE VECTOR INDEX vector_index_token IF NOT EXISTS
 			FOR (n:Token) ON (n.embedding)
-			OPTIONS {indexConfig: { "vector.dimensions": 768, "vector.similarity_function": "cosine" }}
-		`, nil)
+			OPTIONS {indexConfig: { "vector.dimensions": %d, "vector.similarity_function": "cosine" }}
+		`, dim), nil)
 		if err != nil {
-			panic(fmt.Sprintf("Failed to create Neo4j index: %v", err))
+			return fmt.Errorf("failed to create Neo4j index: %v", err)
 		}
+
+		graphRetriever = NewGraphRetriever(neo4jDriver)
 	}
+
+	return nil
 }
 
-func AddDocument(content string, embedding []float32) error {
+func AddDocument(ctx context.Context, content string, embedding []float32) error {
 	var docID int
-	err := pgConn.QueryRow(context.Background(), `
+	err := pgConn.QueryRow(ctx, `
 		INSERT INTO documents (content, embedding)
 		VALUES ($1, $2)
 		RETURNING id
@@ -86,7 +97,7 @@ func AddDocument(content string, embedding []float32) error {
 	}
 
 	if neo4jDriver != nil {
-		return AddTokenAndTriplets(content, embedding, docID)
+		return AddTokenAndTriplets(ctx, content, embedding, docID, defaultExtractor)
 	}
 	return nil
 }
@@ -97,14 +108,25 @@ type Triplet struct {
 	Object    string
 }
 
-func AddTokenAndTriplets(content string, embedding []float32, docID int) error {
-	tokens, triplets := extractTokensAndTriplets(content)
+// AddTokenAndTriplets extracts tokens and triplets from content using the
+// given TripletExtractor and writes them into the Neo4j graph. Callers can
+// pass any TripletExtractor implementation (Gemini, spaCy-over-HTTP, Ollama,
+// offline models) instead of being locked into one provider.
+func AddTokenAndTriplets(ctx context.Context, content string, embedding []float32, docID int, tripletExtractor extractor.TripletExtractor) error {
+	extracted, err := tripletExtractor.Extract(ctx, content)
+	if err != nil {
+		return fmt.Errorf("failed to extract tokens and triplets: %v", err)
+	}
+	tokens := extracted.Tokens
+	triplets := make([]Triplet, 0, len(extracted.Triplets))
+	for _, t := range extracted.Triplets {
+		triplets = append(triplets, Triplet{Subject: t.Subject, Predicate: t.Predicate, Object: t.Object})
+	}
 
-	ctx := context.Background()
 	session := neo4jDriver.NewSession(ctx, neo4j.SessionConfig{})
 	defer session.Close(ctx)
 
-	_, err := session.Run(ctx, `
+	_, err = session.Run(ctx, `
 		MERGE (d:Document {id: $docID, content: $content})
 	`, map[string]interface{}{
 		"docID":   docID,
@@ -115,7 +137,7 @@ func AddTokenAndTriplets(content string, embedding []float32, docID int) error {
 	}
 
 	for _, token := range tokens {
-		tokenEmbedding, err := GetEmbedding(token)
+		tokenEmbedding, err := GetEmbedding(ctx, token)
 		if err != nil {
 			return err
 		}
@@ -136,9 +158,9 @@ func AddTokenAndTriplets(content string, embedding []float32, docID int) error {
 	}
 
 	for _, triplet := range triplets {
-		subjectEmb, _ := GetEmbedding(triplet.Subject)
-		predicateEmb, _ := GetEmbedding(triplet.Predicate)
-		objectEmb, _ := GetEmbedding(triplet.Object)
+		subjectEmb, _ := GetEmbedding(ctx, triplet.Subject)
+		predicateEmb, _ := GetEmbedding(ctx, triplet.Predicate)
+		objectEmb, _ := GetEmbedding(ctx, triplet.Object)
 		_, err := session.Run(ctx, `
 			MERGE (s:Token {name: $subject})
 			ON CREATE SET s.embedding = $subjectEmb
@@ -162,69 +184,107 @@ func AddTokenAndTriplets(content string, embedding []float32, docID int) error {
 	return nil
 }
 
-func extractTokensAndTriplets(content string) ([]string, []Triplet) {
-	cmd := exec.Command("python3", "extract_triplets.py")
-	cmd.Stdin = strings.NewReader(content)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err := cmd.Run()
-	if err != nil {
-		return strings.Split(content, " "), nil
+// defaultTopK bounds how many fused results SearchDocuments returns.
+const defaultTopK = 5
+
+// SearchDocuments runs the hybrid (pgvector + full-text) retriever and, when
+// useGraph is set, merges in a multi-hop GraphRetriever walk of up to
+// maxHops PREDICATE edges. The two rankings are fused with Reciprocal Rank
+// Fusion. Results are returned as structured SearchResult values so callers
+// can render either the plain-text default or the `?format=structured` JSON
+// form.
+func SearchDocuments(ctx context.Context, queryEmbedding []float32, query string, useGraph bool, maxHops, topK int) ([]SearchResult, error) {
+	if topK <= 0 {
+		topK = defaultTopK
+	}
+	if maxHops <= 0 {
+		maxHops = defaultMaxHops
 	}
 
-	var result struct {
-		Tokens   []string  `json:"tokens"`
-		Triplets []Triplet `json:"triplets"`
+	hybridResults, err := hybridSearch(ctx, queryEmbedding, query, topK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run hybrid search: %v", err)
 	}
-	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
-		return strings.Split(content, " "), nil
+
+	if !useGraph || graphRetriever == nil {
+		return hybridResults, nil
 	}
-	return result.Tokens, result.Triplets
-}
 
-func SearchDocuments(queryEmbedding []float32, useGraph bool) (string, error) {
-	var pgContent string
-	err := pgConn.QueryRow(context.Background(), `
-		SELECT content
-		FROM documents
-		ORDER BY embedding <-> $1
-		LIMIT 1
-	`, queryEmbedding).Scan(&pgContent)
+	graphRetrieval, err := graphRetriever.Retrieve(ctx, queryEmbedding, maxHops, topK)
 	if err != nil {
-		return "", fmt.Errorf("failed to search documents in PostgreSQL: %v", err)
+		return hybridResults, fmt.Errorf("failed to run graph retrieval: %v", err)
+	}
+
+	fused := fuseWithGraph(query, hybridResults, graphRetrieval)
+	if len(fused) > topK {
+		fused = fused[:topK]
 	}
+	return fused, nil
+}
 
-	if !useGraph || neo4jDriver == nil {
-		return pgContent, nil
+// fuseWithGraph merges the vector+full-text hybrid ranking with a
+// GraphRetriever ranking via RRF.
+func fuseWithGraph(query string, hybridResults []SearchResult, graphRetrieval GraphRetrievalResult) []SearchResult {
+	hybridRanking := make([]rankedDoc, len(hybridResults))
+	hybridSource := make(map[int]Source, len(hybridResults))
+	content := make(map[int]string, len(hybridResults)+len(graphRetrieval.Content))
+	for i, r := range hybridResults {
+		hybridRanking[i] = rankedDoc{docID: r.DocID, content: r.Content, rank: i + 1}
+		hybridSource[r.DocID] = r.Source
+		content[r.DocID] = r.Content
+	}
+	for docID, c := range graphRetrieval.Content {
+		content[docID] = c
 	}
 
-	ctx := context.Background()
-	session := neo4jDriver.NewSession(ctx, neo4j.SessionConfig{})
-	defer session.Close(ctx)
+	scores := reciprocalRankFusion(hybridRanking, graphRetrieval.Ranking)
+	inGraph := make(map[int]bool, len(graphRetrieval.Ranking))
+	for _, d := range graphRetrieval.Ranking {
+		inGraph[d.docID] = true
+	}
 
-	result, err := session.Run(ctx, `
-		CALL db.index.vector.queryNodes('vector_index_token', 10, $queryEmbedding)
-		YIELD node AS token, score
-		MATCH (token)-[:CONTAINS]->(doc:Document)
-		RETURN doc.content AS content
-		LIMIT 1
-	`, map[string]interface{}{
-		"queryEmbedding": queryEmbedding,
-	})
-	if err != nil {
-		return pgContent, fmt.Errorf("failed to search documents in Neo4j: %v", err)
+	fused := make([]SearchResult, 0, len(content))
+	for docID, c := range content {
+		source, ok := hybridSource[docID]
+		if !ok || inGraph[docID] {
+			source = SourceGraph
+			if ok {
+				source = SourceHybrid
+			}
+		}
+		fused = append(fused, SearchResult{
+			DocID:   docID,
+			Content: c,
+			Score:   scores[docID],
+			Source:  source,
+			Matches: highlightMatches(query, c),
+		})
 	}
 
-	var neo4jContent string
-	if result.Next(ctx) {
-		neo4jContent = result.Record().Values[0].(string)
+	sort.Slice(fused, func(i, j int) bool { return fused[i].Score > fused[j].Score })
+	return fused
+}
+
+// ExplainGraphRetrieval runs the GraphRetriever in isolation and returns the
+// triplet path that led to docID, for the /graph/explain endpoint.
+func ExplainGraphRetrieval(ctx context.Context, queryEmbedding []float32, docID, maxHops, topK int) (TripletPath, bool, error) {
+	if graphRetriever == nil {
+		return TripletPath{}, false, fmt.Errorf("graph retrieval is not enabled: NEO4J_URI is not set")
+	}
+	if topK <= 0 {
+		topK = defaultTopK
+	}
+	if maxHops <= 0 {
+		maxHops = defaultMaxHops
 	}
 
-	context := pgContent
-	if neo4jContent != "" {
-		context += "\nGraph context: " + neo4jContent
+	result, err := graphRetriever.Retrieve(ctx, queryEmbedding, maxHops, topK)
+	if err != nil {
+		return TripletPath{}, false, fmt.Errorf("failed to run graph retrieval: %v", err)
 	}
-	return context, nil
+
+	path, ok := result.PathsByDoc[docID]
+	return path, ok, nil
 }
 
 func CloseConnection() {